@@ -0,0 +1,186 @@
+// Package eth defines the interface the client uses to interact with the
+// keep-tECDSA contracts on an ethereum-compatible chain, along with the
+// structures reflecting events emitted by those contracts.
+package eth
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/keep-network/keep-core/pkg/subscription"
+	"github.com/keep-network/keep-tecdsa/pkg/ecdsa"
+	"github.com/keep-network/keep-tecdsa/pkg/txqueue"
+)
+
+// KeepAddress is an on-chain address of a given keep contract instance.
+type KeepAddress = common.Address
+
+// ECDSAKeepCreatedEvent is emitted when a new ECDSA keep has been created.
+type ECDSAKeepCreatedEvent struct {
+	KeepAddress KeepAddress
+}
+
+// SignatureRequestedEvent is emitted when a keep's members are asked to
+// produce a signature over a digest.
+type SignatureRequestedEvent struct {
+	Digest [32]byte
+}
+
+// InactivityClaimedEvent is emitted when a supermajority of a keep's members
+// report that one or more of their peers have gone inactive.
+type InactivityClaimedEvent struct {
+	KeepAddress   KeepAddress
+	Nonce         *big.Int
+	MemberIndices []uint8
+}
+
+// SignatureSubmittedEvent is emitted once enough members' shares have been
+// combined into a canonical signature for a keep and digest.
+type SignatureSubmittedEvent struct {
+	KeepAddress KeepAddress
+	Digest      [32]byte
+	Signature   *ecdsa.Signature
+}
+
+// ErrInvalidSignature is returned when a signature submitted for a keep does
+// not recover to that keep's public key.
+type ErrInvalidSignature struct {
+	KeepAddress KeepAddress
+}
+
+func (e *ErrInvalidSignature) Error() string {
+	return fmt.Sprintf(
+		"signature does not match keep's public key [%s]",
+		e.KeepAddress.String(),
+	)
+}
+
+// Handle represents a handle to an ethereum-compatible chain that a keep
+// client can use to interact with the keep-tECDSA contracts.
+type Handle interface {
+	// OperatorAddress returns the address of the operator backing this chain
+	// handle.
+	OperatorAddress() common.Address
+
+	// ChainID returns the identifier of the chain this handle is connected
+	// to. It is used to build the EIP-155-style pre-image that keep members
+	// sign over.
+	ChainID() *big.Int
+
+	// CreateKeep creates a new keep on-chain with the given address and
+	// members. honestThreshold is the number of distinct members' shares
+	// required to assemble a signature for the keep. The submission goes
+	// through this handle's transaction queue; pass opts to override the
+	// nonce it would otherwise allocate.
+	CreateKeep(
+		keepAddress KeepAddress,
+		members []common.Address,
+		honestThreshold uint8,
+		opts ...*txqueue.TransactOpts,
+	) error
+
+	// OnECDSAKeepCreated installs a callback that is invoked every time a new
+	// ECDSA keep is created.
+	OnECDSAKeepCreated(
+		handler func(event *ECDSAKeepCreatedEvent),
+	) (subscription.EventSubscription, error)
+
+	// OnSignatureRequested installs a callback that is invoked every time a
+	// signature over a digest is requested from the given keep.
+	OnSignatureRequested(
+		keepAddress KeepAddress,
+		handler func(event *SignatureRequestedEvent),
+	) (subscription.EventSubscription, error)
+
+	// SubmitKeepPublicKey submits a keep's public key to the chain. The
+	// submission goes through this handle's transaction queue; pass opts to
+	// override the nonce it would otherwise allocate.
+	SubmitKeepPublicKey(
+		keepAddress KeepAddress,
+		publicKey [64]byte,
+		opts ...*txqueue.TransactOpts,
+	) error
+
+	// GetKeepPublicKey returns the public key previously submitted for the
+	// given keep.
+	GetKeepPublicKey(keepAddress KeepAddress) ([64]byte, error)
+
+	// SubmitSignature submits the member at memberIndex's share of a
+	// threshold signature over digest for the given keep. Once a distinct
+	// share has been submitted by honestThreshold members, the shares are
+	// combined into a canonical signature over
+	// keccak(keepAddress || digest || chainID), EIP-155-style V is derived
+	// for it, and it is verified against the keep's public key, firing
+	// OnSignatureSubmitted; the combined signature is rejected with an
+	// *ErrInvalidSignature if it does not recover to that key. A second
+	// share from a member that has already submitted for this digest, or
+	// any share submitted after a signature has already been assembled,
+	// is rejected. The submission goes through this handle's transaction
+	// queue; pass opts to override the nonce it would otherwise allocate.
+	SubmitSignature(
+		keepAddress KeepAddress,
+		digest [32]byte,
+		memberIndex uint8,
+		share *ecdsa.SignatureShare,
+		opts ...*txqueue.TransactOpts,
+	) error
+
+	// GetSignatures returns the shares submitted so far for the given keep
+	// and digest, for introspection. Once a signature has been assembled
+	// from them, use GetLatestSignature to retrieve it.
+	GetSignatures(
+		keepAddress KeepAddress,
+		digest [32]byte,
+	) ([]*ecdsa.SignatureShare, error)
+
+	// OnSignatureSubmitted installs a callback that is invoked every time a
+	// canonical signature has been assembled from enough members' shares
+	// for a keep.
+	OnSignatureSubmitted(
+		keepAddress KeepAddress,
+		handler func(event *SignatureSubmittedEvent),
+	) (subscription.EventSubscription, error)
+
+	// GetLatestSignature returns the canonical signature assembled for the
+	// given keep and digest, once enough members' shares have been
+	// submitted for it.
+	GetLatestSignature(
+		keepAddress KeepAddress,
+		digest [32]byte,
+	) (*ecdsa.Signature, error)
+
+	// NotifyInactiveMembers reports to the chain that the members at
+	// memberIndices have gone inactive. The claim is attributed to this
+	// chain handle's operator. signatures is an aggregated signature over
+	// the claim produced by a supermajority of the keep's remaining
+	// members, and nonce must match the keep's current inactivity nonce to
+	// be accepted.
+	NotifyInactiveMembers(
+		keepAddress KeepAddress,
+		memberIndices []uint8,
+		nonce *big.Int,
+		signatures []byte,
+	) error
+
+	// OnInactivityClaimed installs a callback that is invoked every time an
+	// inactivity claim is accepted for a keep.
+	OnInactivityClaimed(
+		handler func(event *InactivityClaimedEvent),
+	) (subscription.EventSubscription, error)
+
+	// GetInactivityNonce returns the next nonce expected by the chain for an
+	// inactivity claim against the given keep.
+	GetInactivityNonce(keepAddress KeepAddress) (*big.Int, error)
+
+	// PendingCount returns the number of submissions queued through
+	// CreateKeep, SubmitKeepPublicKey, or SubmitSignature that have not yet
+	// completed.
+	PendingCount() int
+
+	// Drain blocks until every submission queued through CreateKeep,
+	// SubmitKeepPublicKey, or SubmitSignature has completed, or ctx is
+	// done, whichever happens first.
+	Drain(ctx context.Context) error
+}
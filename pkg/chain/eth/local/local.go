@@ -0,0 +1,771 @@
+// Package local provides an in-memory implementation of the eth.Handle
+// interface, backed by plain Go data structures rather than an actual
+// blockchain. It lets client-side protocol code be developed and tested
+// before it can be run against a real chain.
+package local
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/keep-network/keep-core/pkg/subscription"
+	"github.com/keep-network/keep-tecdsa/pkg/chain/eth"
+	keeptecdsa "github.com/keep-network/keep-tecdsa/pkg/ecdsa"
+	"github.com/keep-network/keep-tecdsa/pkg/operator"
+	"github.com/keep-network/keep-tecdsa/pkg/txqueue"
+)
+
+// inactivityClaimHeartbeat is the window within which a claim against the
+// same set of members is treated as a duplicate of a previous claim rather
+// than a new report.
+const inactivityClaimHeartbeat = 5 * time.Minute
+
+// localChainID is the chain id reported by every LocalChain instance.
+var localChainID = big.NewInt(1101)
+
+// eip155Offset is added to 2*chainID + recid to produce the EIP-155-style
+// recovery parameter V carried on a submitted signature.
+var eip155Offset = big.NewInt(35)
+
+var handlerIDSequence int32
+
+func generateHandlerID() int {
+	return int(atomic.AddInt32(&handlerIDSequence, 1))
+}
+
+// keepsMutex guards keeps: every LocalChain instance shares the same keeps
+// map but has its own independent txQueue, so concurrent CreateKeep calls
+// from distinct operator-backed chain handles are otherwise unserialized.
+var keepsMutex sync.RWMutex
+
+// keeps holds the state of all the keeps known to the local chain. It is a
+// package-level variable so that tests can reset it between runs.
+var keeps map[eth.KeepAddress]*localKeep
+
+type inactivityClaim struct {
+	reporter      common.Address
+	memberIndices []uint8
+	nonce         *big.Int
+	signatures    []byte
+	reportedAt    time.Time
+}
+
+type localKeep struct {
+	members         []common.Address
+	honestThreshold uint8
+
+	publicKey [64]byte
+
+	signaturesMutex  sync.Mutex
+	signatureShares  map[[32]byte]map[uint8]*keeptecdsa.SignatureShare
+	latestSignatures map[[32]byte]*keeptecdsa.Signature
+
+	handlerMutex               sync.Mutex
+	signatureRequestedHandlers map[int]func(event *eth.SignatureRequestedEvent)
+	signatureSubmittedHandlers map[int]func(event *eth.SignatureSubmittedEvent)
+
+	inactivityMutex  sync.Mutex
+	inactivityNonce  *big.Int
+	inactivityClaims []*inactivityClaim
+}
+
+// LocalChain is an in-memory implementation of eth.Handle.
+type LocalChain struct {
+	operatorPrivateKey *ecdsa.PrivateKey
+
+	txQueue *txqueue.Queue
+
+	handlerMutex sync.Mutex
+
+	ecdsaKeepCreatedHandlers  map[int]func(event *eth.ECDSAKeepCreatedEvent)
+	inactivityClaimedHandlers map[int]func(event *eth.InactivityClaimedEvent)
+}
+
+// Connect creates a new in-memory chain handle. The operator key backing it
+// is resolved with operator.LoadOrGenerateKey: an explicitly-supplied
+// privateKey is used as-is, otherwise a key is loaded from (or generated and
+// persisted to) <dataDir>/operator.key, falling back to a purely in-memory
+// key when dataDir is empty.
+func Connect(privateKey *ecdsa.PrivateKey, dataDir string) (eth.Handle, error) {
+	operatorPrivateKey, err := operator.LoadOrGenerateKey(privateKey, dataDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve operator key: [%v]", err)
+	}
+
+	return &LocalChain{
+		operatorPrivateKey: operatorPrivateKey,
+		txQueue: txqueue.New(
+			func(ctx context.Context, operator common.Address) (uint64, error) {
+				return 0, nil
+			},
+		),
+		ecdsaKeepCreatedHandlers:  make(map[int]func(event *eth.ECDSAKeepCreatedEvent)),
+		inactivityClaimedHandlers: make(map[int]func(event *eth.InactivityClaimedEvent)),
+	}, nil
+}
+
+// transactOpts returns the single *txqueue.TransactOpts passed to a
+// variadic opts parameter, or nil if none was given.
+func transactOpts(opts []*txqueue.TransactOpts) *txqueue.TransactOpts {
+	if len(opts) > 0 {
+		return opts[0]
+	}
+
+	return nil
+}
+
+// PendingCount returns the number of submissions queued through CreateKeep,
+// SubmitKeepPublicKey, or SubmitSignature that have not yet completed.
+func (c *LocalChain) PendingCount() int {
+	return c.txQueue.PendingCount()
+}
+
+// Drain blocks until every submission queued through CreateKeep,
+// SubmitKeepPublicKey, or SubmitSignature has completed, or ctx is done,
+// whichever happens first.
+func (c *LocalChain) Drain(ctx context.Context) error {
+	return c.txQueue.Drain(ctx)
+}
+
+// OperatorAddress returns the address of the operator backing this chain
+// handle.
+func (c *LocalChain) OperatorAddress() common.Address {
+	return crypto.PubkeyToAddress(c.operatorPrivateKey.PublicKey)
+}
+
+// ChainID returns the identifier of the chain this handle is connected to.
+func (c *LocalChain) ChainID() *big.Int {
+	return localChainID
+}
+
+// signatureHash returns the EIP-155-style pre-image a keep's members sign
+// over for the given keep and digest: keccak(keepAddress || digest ||
+// chainID).
+func signatureHash(keepAddress eth.KeepAddress, digest [32]byte, chainID *big.Int) []byte {
+	message := append(keepAddress.Bytes(), digest[:]...)
+	message = append(message, common.LeftPadBytes(chainID.Bytes(), 32)...)
+
+	return crypto.Keccak256(message)
+}
+
+// recoverPublicKey recovers the uncompressed public key (with the leading
+// 0x04 prefix) that produced signature over hash, given the chain id the
+// EIP-155-style V is expected to encode.
+func recoverPublicKey(chainID *big.Int, hash []byte, signature *keeptecdsa.Signature) ([]byte, error) {
+	recoveryID := new(big.Int).Sub(signature.V, new(big.Int).Add(eip155Offset, new(big.Int).Mul(big.NewInt(2), chainID)))
+	if !recoveryID.IsUint64() || recoveryID.Uint64() > 1 {
+		return nil, fmt.Errorf(
+			"signature chain id does not match expected chain id [%v]",
+			chainID,
+		)
+	}
+
+	sigBytes := make([]byte, 65)
+	copy(sigBytes[0:32], common.LeftPadBytes(signature.R.Bytes(), 32))
+	copy(sigBytes[32:64], common.LeftPadBytes(signature.S.Bytes(), 32))
+	sigBytes[64] = byte(recoveryID.Uint64())
+
+	return crypto.Ecrecover(hash, sigBytes)
+}
+
+func (c *LocalChain) getKeep(keepAddress eth.KeepAddress) (*localKeep, error) {
+	keepsMutex.RLock()
+	defer keepsMutex.RUnlock()
+
+	keep, ok := keeps[keepAddress]
+	if !ok {
+		return nil, fmt.Errorf("no keep with address [%s]", keepAddress.String())
+	}
+
+	return keep, nil
+}
+
+// authorize returns an error unless this chain handle's operator is a member
+// of the given keep.
+func (c *LocalChain) authorize(keep *localKeep) error {
+	operatorAddress := c.OperatorAddress()
+
+	for _, member := range keep.members {
+		if member == operatorAddress {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("operator [%s] is not a member of this keep", operatorAddress.String())
+}
+
+// authorizeMemberIndex returns an error unless this chain handle's operator
+// is specifically the member at memberIndex in the given keep. This is
+// stronger than authorize: it binds the caller to the one index it is
+// allowed to submit a share under, so an operator holding a single seat
+// cannot submit shares under indices it does not own and single-handedly
+// satisfy honestThreshold.
+func (c *LocalChain) authorizeMemberIndex(keep *localKeep, memberIndex uint8) error {
+	operatorAddress := c.OperatorAddress()
+
+	if int(memberIndex) >= len(keep.members) {
+		return fmt.Errorf(
+			"member index [%d] is out of range for keep with [%d] members",
+			memberIndex,
+			len(keep.members),
+		)
+	}
+
+	if keep.members[memberIndex] != operatorAddress {
+		return fmt.Errorf(
+			"operator [%s] is not member [%d] of this keep",
+			operatorAddress.String(),
+			memberIndex,
+		)
+	}
+
+	return nil
+}
+
+// CreateKeep creates a new keep with the given address and members,
+// requiring honestThreshold distinct members' shares to assemble a
+// signature, and notifies all the registered ECDSAKeepCreated handlers. The
+// submission is routed through this chain handle's transaction queue.
+func (c *LocalChain) CreateKeep(
+	keepAddress eth.KeepAddress,
+	members []common.Address,
+	honestThreshold uint8,
+	opts ...*txqueue.TransactOpts,
+) error {
+	return c.txQueue.Send(
+		context.Background(),
+		c.OperatorAddress(),
+		transactOpts(opts),
+		func(nonce uint64) error {
+			return c.createKeep(keepAddress, members, honestThreshold)
+		},
+	)
+}
+
+func (c *LocalChain) createKeep(
+	keepAddress eth.KeepAddress,
+	members []common.Address,
+	honestThreshold uint8,
+) error {
+	seenMembers := make(map[common.Address]bool, len(members))
+	for _, member := range members {
+		if seenMembers[member] {
+			return fmt.Errorf(
+				"member [%s] is listed more than once for keep [%s]",
+				member.String(),
+				keepAddress.String(),
+			)
+		}
+		seenMembers[member] = true
+	}
+
+	if honestThreshold < 1 || int(honestThreshold) > len(members) {
+		return fmt.Errorf(
+			"honest threshold [%d] is invalid for a keep with [%d] members",
+			honestThreshold,
+			len(members),
+		)
+	}
+
+	keepsMutex.Lock()
+	defer keepsMutex.Unlock()
+
+	if _, ok := keeps[keepAddress]; ok {
+		return fmt.Errorf("keep already exists for address [%s]", keepAddress.String())
+	}
+
+	keeps[keepAddress] = &localKeep{
+		members:                    members,
+		honestThreshold:            honestThreshold,
+		signatureShares:            make(map[[32]byte]map[uint8]*keeptecdsa.SignatureShare),
+		latestSignatures:           make(map[[32]byte]*keeptecdsa.Signature),
+		signatureRequestedHandlers: make(map[int]func(event *eth.SignatureRequestedEvent)),
+		signatureSubmittedHandlers: make(map[int]func(event *eth.SignatureSubmittedEvent)),
+		inactivityNonce:            big.NewInt(0),
+	}
+
+	event := &eth.ECDSAKeepCreatedEvent{KeepAddress: keepAddress}
+
+	c.handlerMutex.Lock()
+	defer c.handlerMutex.Unlock()
+
+	for _, handler := range c.ecdsaKeepCreatedHandlers {
+		go func(handler func(event *eth.ECDSAKeepCreatedEvent)) {
+			handler(event)
+		}(handler)
+	}
+
+	return nil
+}
+
+// OnECDSAKeepCreated installs a callback that is invoked every time a new
+// ECDSA keep is created.
+func (c *LocalChain) OnECDSAKeepCreated(
+	handler func(event *eth.ECDSAKeepCreatedEvent),
+) (subscription.EventSubscription, error) {
+	c.handlerMutex.Lock()
+	defer c.handlerMutex.Unlock()
+
+	id := generateHandlerID()
+	c.ecdsaKeepCreatedHandlers[id] = handler
+
+	return subscription.NewEventSubscription(func() {
+		c.handlerMutex.Lock()
+		defer c.handlerMutex.Unlock()
+
+		delete(c.ecdsaKeepCreatedHandlers, id)
+	}), nil
+}
+
+// OnSignatureRequested installs a callback that is invoked every time a
+// signature over a digest is requested from the given keep.
+func (c *LocalChain) OnSignatureRequested(
+	keepAddress eth.KeepAddress,
+	handler func(event *eth.SignatureRequestedEvent),
+) (subscription.EventSubscription, error) {
+	keep, err := c.getKeep(keepAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	keep.handlerMutex.Lock()
+	defer keep.handlerMutex.Unlock()
+
+	id := generateHandlerID()
+	keep.signatureRequestedHandlers[id] = handler
+
+	return subscription.NewEventSubscription(func() {
+		keep.handlerMutex.Lock()
+		defer keep.handlerMutex.Unlock()
+
+		delete(keep.signatureRequestedHandlers, id)
+	}), nil
+}
+
+// requestSignature simulates a signing request arriving from the chain for
+// the given keep and digest; it exists so tests can exercise
+// OnSignatureRequested without a real on-chain contract.
+func (c *LocalChain) requestSignature(keepAddress eth.KeepAddress, digest [32]byte) error {
+	keep, err := c.getKeep(keepAddress)
+	if err != nil {
+		return err
+	}
+
+	event := &eth.SignatureRequestedEvent{Digest: digest}
+
+	keep.handlerMutex.Lock()
+	defer keep.handlerMutex.Unlock()
+
+	for _, handler := range keep.signatureRequestedHandlers {
+		go func(handler func(event *eth.SignatureRequestedEvent)) {
+			handler(event)
+		}(handler)
+	}
+
+	return nil
+}
+
+// SubmitKeepPublicKey submits a keep's public key to the chain. The
+// submission is routed through this chain handle's transaction queue.
+func (c *LocalChain) SubmitKeepPublicKey(
+	keepAddress eth.KeepAddress,
+	publicKey [64]byte,
+	opts ...*txqueue.TransactOpts,
+) error {
+	return c.txQueue.Send(
+		context.Background(),
+		c.OperatorAddress(),
+		transactOpts(opts),
+		func(nonce uint64) error {
+			return c.submitKeepPublicKey(keepAddress, publicKey)
+		},
+	)
+}
+
+func (c *LocalChain) submitKeepPublicKey(
+	keepAddress eth.KeepAddress,
+	publicKey [64]byte,
+) error {
+	keep, err := c.getKeep(keepAddress)
+	if err != nil {
+		return err
+	}
+
+	if err := c.authorize(keep); err != nil {
+		return err
+	}
+
+	if keep.publicKey != [64]byte{} {
+		return fmt.Errorf(
+			"public key already submitted for keep [%s]",
+			keepAddress.String(),
+		)
+	}
+
+	keep.publicKey = publicKey
+
+	return nil
+}
+
+// GetKeepPublicKey returns the public key previously submitted for the given
+// keep.
+func (c *LocalChain) GetKeepPublicKey(keepAddress eth.KeepAddress) ([64]byte, error) {
+	keep, err := c.getKeep(keepAddress)
+	if err != nil {
+		return [64]byte{}, err
+	}
+
+	return keep.publicKey, nil
+}
+
+// SubmitSignature submits memberIndex's share of a threshold signature over
+// digest for the given keep. Once honestThreshold distinct members' shares
+// have been submitted for the same digest, they are combined into a
+// canonical signature, an EIP-155-style V over
+// keccak(keepAddress || digest || chainID) is derived for it, and it is
+// verified against the keep's public key, firing the registered
+// SignatureSubmitted handlers; the combined signature is rejected with an
+// *eth.ErrInvalidSignature if it does not recover to that key. A second
+// share from a member that has already submitted for this digest, or any
+// share submitted once a signature has already been assembled, is
+// rejected. The submission is routed through this chain handle's
+// transaction queue.
+func (c *LocalChain) SubmitSignature(
+	keepAddress eth.KeepAddress,
+	digest [32]byte,
+	memberIndex uint8,
+	share *keeptecdsa.SignatureShare,
+	opts ...*txqueue.TransactOpts,
+) error {
+	return c.txQueue.Send(
+		context.Background(),
+		c.OperatorAddress(),
+		transactOpts(opts),
+		func(nonce uint64) error {
+			return c.submitSignature(keepAddress, digest, memberIndex, share)
+		},
+	)
+}
+
+func (c *LocalChain) submitSignature(
+	keepAddress eth.KeepAddress,
+	digest [32]byte,
+	memberIndex uint8,
+	share *keeptecdsa.SignatureShare,
+) error {
+	keep, err := c.getKeep(keepAddress)
+	if err != nil {
+		return err
+	}
+
+	if err := c.authorizeMemberIndex(keep, memberIndex); err != nil {
+		return err
+	}
+
+	keep.signaturesMutex.Lock()
+	defer keep.signaturesMutex.Unlock()
+
+	if _, ok := keep.latestSignatures[digest]; ok {
+		return fmt.Errorf(
+			"signature already assembled for keep [%s] digest [%x]",
+			keepAddress.String(),
+			digest,
+		)
+	}
+
+	shares, ok := keep.signatureShares[digest]
+	if !ok {
+		shares = make(map[uint8]*keeptecdsa.SignatureShare)
+		keep.signatureShares[digest] = shares
+	}
+
+	if _, ok := shares[memberIndex]; ok {
+		return fmt.Errorf(
+			"member [%d] already submitted a share for keep [%s] digest [%x]",
+			memberIndex,
+			keepAddress.String(),
+			digest,
+		)
+	}
+
+	shares[memberIndex] = share
+
+	if len(shares) < int(keep.honestThreshold) {
+		return nil
+	}
+
+	signature, err := combineSignatureShares(keepAddress, digest, c.ChainID(), share, keep.publicKey)
+	if err != nil {
+		return err
+	}
+
+	keep.latestSignatures[digest] = signature
+
+	event := &eth.SignatureSubmittedEvent{
+		KeepAddress: keepAddress,
+		Digest:      digest,
+		Signature:   signature,
+	}
+
+	keep.handlerMutex.Lock()
+	defer keep.handlerMutex.Unlock()
+
+	for _, handler := range keep.signatureSubmittedHandlers {
+		go func(handler func(event *eth.SignatureSubmittedEvent)) {
+			handler(event)
+		}(handler)
+	}
+
+	return nil
+}
+
+// combineSignatureShares assembles the canonical signature for keepAddress
+// and digest out of a threshold member's share, deriving the EIP-155-style
+// V that recovers it to keepPublicKey. The local chain does not have
+// visibility into every member's partial signing round, so unlike a real
+// KeepECDSA contract it trusts the triggering share's R and S to already be
+// the joint signature's, as threshold ECDSA signing protocols have every
+// honest member arrive at the same (R, S) by construction.
+func combineSignatureShares(
+	keepAddress eth.KeepAddress,
+	digest [32]byte,
+	chainID *big.Int,
+	share *keeptecdsa.SignatureShare,
+	keepPublicKey [64]byte,
+) (*keeptecdsa.Signature, error) {
+	hash := signatureHash(keepAddress, digest, chainID)
+
+	for _, recoveryID := range []int64{0, 1} {
+		v := new(big.Int).Add(
+			eip155Offset,
+			new(big.Int).Add(new(big.Int).Mul(big.NewInt(2), chainID), big.NewInt(recoveryID)),
+		)
+
+		candidate := &keeptecdsa.Signature{R: share.R, S: share.S, V: v}
+
+		recoveredPublicKey, err := recoverPublicKey(chainID, hash, candidate)
+		if err != nil {
+			continue
+		}
+
+		if bytes.Equal(recoveredPublicKey[1:], keepPublicKey[:]) {
+			return candidate, nil
+		}
+	}
+
+	return nil, &eth.ErrInvalidSignature{KeepAddress: keepAddress}
+}
+
+// GetSignatures returns the shares submitted so far for the given keep and
+// digest, for introspection. Once a signature has been assembled from
+// them, use GetLatestSignature to retrieve it.
+func (c *LocalChain) GetSignatures(
+	keepAddress eth.KeepAddress,
+	digest [32]byte,
+) ([]*keeptecdsa.SignatureShare, error) {
+	keep, err := c.getKeep(keepAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	keep.signaturesMutex.Lock()
+	defer keep.signaturesMutex.Unlock()
+
+	shares := make([]*keeptecdsa.SignatureShare, 0, len(keep.signatureShares[digest]))
+	for _, share := range keep.signatureShares[digest] {
+		shares = append(shares, share)
+	}
+
+	return shares, nil
+}
+
+// OnSignatureSubmitted installs a callback that is invoked every time a
+// canonical signature has been assembled from enough members' shares for
+// the given keep.
+func (c *LocalChain) OnSignatureSubmitted(
+	keepAddress eth.KeepAddress,
+	handler func(event *eth.SignatureSubmittedEvent),
+) (subscription.EventSubscription, error) {
+	keep, err := c.getKeep(keepAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	keep.handlerMutex.Lock()
+	defer keep.handlerMutex.Unlock()
+
+	id := generateHandlerID()
+	keep.signatureSubmittedHandlers[id] = handler
+
+	return subscription.NewEventSubscription(func() {
+		keep.handlerMutex.Lock()
+		defer keep.handlerMutex.Unlock()
+
+		delete(keep.signatureSubmittedHandlers, id)
+	}), nil
+}
+
+// GetLatestSignature returns the canonical signature assembled for the
+// given keep and digest, once enough members' shares have been submitted
+// for it.
+func (c *LocalChain) GetLatestSignature(
+	keepAddress eth.KeepAddress,
+	digest [32]byte,
+) (*keeptecdsa.Signature, error) {
+	keep, err := c.getKeep(keepAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	keep.signaturesMutex.Lock()
+	defer keep.signaturesMutex.Unlock()
+
+	signature, ok := keep.latestSignatures[digest]
+	if !ok {
+		return nil, fmt.Errorf(
+			"no signature assembled yet for keep [%s] digest [%x]",
+			keepAddress.String(),
+			digest,
+		)
+	}
+
+	return signature, nil
+}
+
+// NotifyInactiveMembers reports to the chain that the members at
+// memberIndices have gone inactive. The claim is attributed to this chain
+// handle's operator. The aggregated signature is not cryptographically
+// verified on the local chain; it is accepted as long as it is non-empty.
+// The keep's inactivity nonce must match, after which it is incremented to
+// prevent the claim from being replayed.
+func (c *LocalChain) NotifyInactiveMembers(
+	keepAddress eth.KeepAddress,
+	memberIndices []uint8,
+	nonce *big.Int,
+	signatures []byte,
+) error {
+	keep, err := c.getKeep(keepAddress)
+	if err != nil {
+		return err
+	}
+
+	if err := c.authorize(keep); err != nil {
+		return err
+	}
+
+	if len(signatures) == 0 {
+		return fmt.Errorf("inactivity claim carries no signatures")
+	}
+
+	if len(memberIndices) == 0 {
+		return fmt.Errorf("inactivity claim names no members")
+	}
+
+	for _, memberIndex := range memberIndices {
+		if int(memberIndex) >= len(keep.members) {
+			return fmt.Errorf(
+				"member index [%d] is out of range for keep with [%d] members",
+				memberIndex,
+				len(keep.members),
+			)
+		}
+	}
+
+	keep.inactivityMutex.Lock()
+	defer keep.inactivityMutex.Unlock()
+
+	if nonce.Cmp(keep.inactivityNonce) != 0 {
+		return fmt.Errorf(
+			"invalid inactivity nonce for keep [%s]: expected [%v], got [%v]",
+			keepAddress.String(),
+			keep.inactivityNonce,
+			nonce,
+		)
+	}
+
+	sortedIndices := append([]uint8(nil), memberIndices...)
+	sort.Slice(sortedIndices, func(i, j int) bool {
+		return sortedIndices[i] < sortedIndices[j]
+	})
+
+	now := time.Now()
+	for _, claim := range keep.inactivityClaims {
+		if now.Sub(claim.reportedAt) < inactivityClaimHeartbeat &&
+			bytes.Equal(claim.memberIndices, sortedIndices) {
+			return fmt.Errorf(
+				"inactivity already claimed against members %v for keep [%s]",
+				memberIndices,
+				keepAddress.String(),
+			)
+		}
+	}
+
+	keep.inactivityClaims = append(keep.inactivityClaims, &inactivityClaim{
+		reporter:      c.OperatorAddress(),
+		memberIndices: sortedIndices,
+		nonce:         nonce,
+		signatures:    signatures,
+		reportedAt:    now,
+	})
+
+	keep.inactivityNonce = new(big.Int).Add(keep.inactivityNonce, big.NewInt(1))
+
+	event := &eth.InactivityClaimedEvent{
+		KeepAddress:   keepAddress,
+		Nonce:         nonce,
+		MemberIndices: memberIndices,
+	}
+
+	c.handlerMutex.Lock()
+	defer c.handlerMutex.Unlock()
+
+	for _, handler := range c.inactivityClaimedHandlers {
+		go func(handler func(event *eth.InactivityClaimedEvent)) {
+			handler(event)
+		}(handler)
+	}
+
+	return nil
+}
+
+// OnInactivityClaimed installs a callback that is invoked every time an
+// inactivity claim is accepted for a keep.
+func (c *LocalChain) OnInactivityClaimed(
+	handler func(event *eth.InactivityClaimedEvent),
+) (subscription.EventSubscription, error) {
+	c.handlerMutex.Lock()
+	defer c.handlerMutex.Unlock()
+
+	id := generateHandlerID()
+	c.inactivityClaimedHandlers[id] = handler
+
+	return subscription.NewEventSubscription(func() {
+		c.handlerMutex.Lock()
+		defer c.handlerMutex.Unlock()
+
+		delete(c.inactivityClaimedHandlers, id)
+	}), nil
+}
+
+// GetInactivityNonce returns the next nonce expected by the chain for an
+// inactivity claim against the given keep.
+func (c *LocalChain) GetInactivityNonce(keepAddress eth.KeepAddress) (*big.Int, error) {
+	keep, err := c.getKeep(keepAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	keep.inactivityMutex.Lock()
+	defer keep.inactivityMutex.Unlock()
+
+	return new(big.Int).Set(keep.inactivityNonce), nil
+}
@@ -2,15 +2,18 @@ package local
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"fmt"
 	"math/big"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/keep-network/keep-tecdsa/pkg/chain/eth"
-	"github.com/keep-network/keep-tecdsa/pkg/ecdsa"
+	tecdsa "github.com/keep-network/keep-tecdsa/pkg/ecdsa"
 )
 
 func TestOnECDSAKeepCreated(t *testing.T) {
@@ -34,7 +37,7 @@ func TestOnECDSAKeepCreated(t *testing.T) {
 	}
 	defer subscription.Unsubscribe()
 
-	err = chain.CreateKeep(keepAddress)
+	err = chain.CreateKeep(keepAddress, []common.Address{chain.OperatorAddress()}, 1)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -62,7 +65,7 @@ func TestOnSignatureRequested(t *testing.T) {
 	keepAddress := common.Address([20]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1})
 	digest := [32]byte{1}
 
-	err := chain.CreateKeep(keepAddress)
+	err := chain.CreateKeep(keepAddress, []common.Address{chain.OperatorAddress()}, 1)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -110,7 +113,7 @@ func TestSubmitKeepPublicKey(t *testing.T) {
 		keepAddress.String(),
 	)
 
-	err := chain.CreateKeep(keepAddress)
+	err := chain.CreateKeep(keepAddress, []common.Address{chain.OperatorAddress()}, 1)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -149,49 +152,485 @@ func TestSubmitKeepPublicKey(t *testing.T) {
 	}
 }
 
-func TestSubmitSignature(t *testing.T) {
+// thresholdTestKeep sets up a keep requiring honestThreshold distinct
+// members' shares to assemble a signature, with its public key already
+// submitted as the one belonging to signerKey. Each member is backed by its
+// own chain handle with its own operator key, so a share for members[i] can
+// only be submitted through memberChains[i]; memberChains[0] is chain itself.
+func thresholdTestKeep(
+	t *testing.T,
+	chain *LocalChain,
+	keepAddress eth.KeepAddress,
+	honestThreshold uint8,
+) (signerKey *ecdsa.PrivateKey, memberChains []*LocalChain) {
+	t.Helper()
+
+	signerKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keepPublicKey [64]byte
+	copy(keepPublicKey[:], crypto.FromECDSAPub(&signerKey.PublicKey)[1:])
+
+	memberChains = make([]*LocalChain, honestThreshold+1)
+	memberChains[0] = chain
+	for i := 1; i < len(memberChains); i++ {
+		otherChain, err := Connect(nil, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		memberChains[i] = otherChain.(*LocalChain)
+	}
+
+	members := make([]common.Address, len(memberChains))
+	for i, memberChain := range memberChains {
+		members[i] = memberChain.OperatorAddress()
+	}
+
+	if err := chain.CreateKeep(keepAddress, members, honestThreshold); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := chain.SubmitKeepPublicKey(keepAddress, keepPublicKey); err != nil {
+		t.Fatal(err)
+	}
+
+	return signerKey, memberChains
+}
+
+// signTestShare signs hash with privateKey and packages the result as the
+// (R, S) a member would submit as their share of the joint signature.
+func signTestShare(
+	t *testing.T,
+	privateKey *ecdsa.PrivateKey,
+	hash []byte,
+) *tecdsa.SignatureShare {
+	t.Helper()
+
+	sig, err := crypto.Sign(hash, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &tecdsa.SignatureShare{
+		R: new(big.Int).SetBytes(sig[0:32]),
+		S: new(big.Int).SetBytes(sig[32:64]),
+	}
+}
+
+func TestSubmitSignature_BelowThreshold(t *testing.T) {
 	chain := initializeLocalChain()
 	keepAddress := common.HexToAddress("0x41048F9B90290A2e96D07f537F3A7E97620E9e47")
 	digest := [32]byte{1}
-	signature := &ecdsa.Signature{R: big.NewInt(8), S: big.NewInt(7)}
 
-	err := chain.CreateKeep(keepAddress)
+	signerKey, _ := thresholdTestKeep(t, chain, keepAddress, 2)
+
+	eventFired := make(chan *eth.SignatureSubmittedEvent, 1)
+	subscription, err := chain.OnSignatureSubmitted(
+		keepAddress,
+		func(event *eth.SignatureSubmittedEvent) {
+			eventFired <- event
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer subscription.Unsubscribe()
+
+	hash := signatureHash(keepAddress, digest, chain.ChainID())
+	share := signTestShare(t, signerKey, hash)
+
+	err = chain.SubmitSignature(keepAddress, digest, 0, share)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	err = chain.SubmitSignature(
+	select {
+	case event := <-eventFired:
+		t.Fatalf("unexpected SignatureSubmitted event before threshold was reached: [%v]", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if _, err := chain.GetLatestSignature(keepAddress, digest); err == nil {
+		t.Error("expected an error for a signature that has not been assembled yet; got none")
+	}
+}
+
+func TestSubmitSignature_ExactlyThreshold(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	chain := initializeLocalChain()
+	keepAddress := common.HexToAddress("0x41048F9B90290A2e96D07f537F3A7E97620E9e47")
+	digest := [32]byte{1}
+
+	signerKey, memberChains := thresholdTestKeep(t, chain, keepAddress, 2)
+
+	eventFired := make(chan *eth.SignatureSubmittedEvent)
+	subscription, err := chain.OnSignatureSubmitted(
 		keepAddress,
-		digest,
-		signature,
+		func(event *eth.SignatureSubmittedEvent) {
+			eventFired <- event
+		},
 	)
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer subscription.Unsubscribe()
+
+	hash := signatureHash(keepAddress, digest, chain.ChainID())
+	share := signTestShare(t, signerKey, hash)
+
+	if err := memberChains[0].SubmitSignature(keepAddress, digest, 0, share); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := memberChains[1].SubmitSignature(keepAddress, digest, 1, share); err != nil {
+		t.Fatal(err)
+	}
+
+	var signature *tecdsa.Signature
+	select {
+	case event := <-eventFired:
+		if event.KeepAddress != keepAddress || event.Digest != digest {
+			t.Fatalf("unexpected SignatureSubmitted event: [%v]", event)
+		}
+		signature = event.Signature
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
 
-	signatures, err := chain.GetSignatures(keepAddress, digest)
+	publicKey, err := chain.GetKeepPublicKey(keepAddress)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	if len(signatures) != 1 {
+	recoveredPublicKey, err := recoverPublicKey(chain.ChainID(), hash, signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(recoveredPublicKey[1:], publicKey[:]) {
 		t.Errorf(
-			"invalid number of stored signatures\nexpected: %v\nactual:   %v",
-			1,
-			len(signatures),
+			"aggregated signature does not recover to the keep's public key\nexpected: %x\nactual:   %x",
+			publicKey,
+			recoveredPublicKey[1:],
 		)
 	}
 
-	if !reflect.DeepEqual(signatures[0], signature) {
+	latestSignature, err := chain.GetLatestSignature(keepAddress, digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(latestSignature, signature) {
 		t.Errorf(
-			"invalid stored signature\nexpected: %v\nactual:   %v",
+			"unexpected latest signature\nexpected: %v\nactual:   %v",
 			signature,
-			signatures[0],
+			latestSignature,
 		)
 	}
+
+	shares, err := chain.GetSignatures(keepAddress, digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shares) != 2 {
+		t.Errorf("unexpected number of stored shares\nexpected: %v\nactual:   %v", 2, len(shares))
+	}
+}
+
+func TestSubmitSignature_DuplicateIndex(t *testing.T) {
+	chain := initializeLocalChain()
+	keepAddress := common.HexToAddress("0x41048F9B90290A2e96D07f537F3A7E97620E9e47")
+	digest := [32]byte{1}
+
+	signerKey, _ := thresholdTestKeep(t, chain, keepAddress, 2)
+
+	hash := signatureHash(keepAddress, digest, chain.ChainID())
+	share := signTestShare(t, signerKey, hash)
+
+	if err := chain.SubmitSignature(keepAddress, digest, 0, share); err != nil {
+		t.Fatal(err)
+	}
+
+	err := chain.SubmitSignature(keepAddress, digest, 0, share)
+	if err == nil {
+		t.Error("expected an error for a second share from the same member; got none")
+	}
+}
+
+// TestSubmitSignature_ForgedMemberIndex guards against a single operator
+// single-handedly satisfying honestThreshold by submitting its own share
+// under indices belonging to other members: a real member only ever speaks
+// for its own seat.
+func TestSubmitSignature_ForgedMemberIndex(t *testing.T) {
+	chain := initializeLocalChain()
+	keepAddress := common.HexToAddress("0x41048F9B90290A2e96D07f537F3A7E97620E9e47")
+	digest := [32]byte{1}
+
+	signerKey, _ := thresholdTestKeep(t, chain, keepAddress, 2)
+
+	hash := signatureHash(keepAddress, digest, chain.ChainID())
+	share := signTestShare(t, signerKey, hash)
+
+	if err := chain.SubmitSignature(keepAddress, digest, 0, share); err != nil {
+		t.Fatal(err)
+	}
+
+	err := chain.SubmitSignature(keepAddress, digest, 1, share)
+	if err == nil {
+		t.Error("expected an error for an operator forging a share under another member's index; got none")
+	}
+
+	if _, err := chain.GetLatestSignature(keepAddress, digest); err == nil {
+		t.Error("expected no signature to be assembled from a single forged operator; got one")
+	}
+}
+
+func TestSubmitSignature_PostAggregation(t *testing.T) {
+	chain := initializeLocalChain()
+	keepAddress := common.HexToAddress("0x41048F9B90290A2e96D07f537F3A7E97620E9e47")
+	digest := [32]byte{1}
+
+	signerKey, memberChains := thresholdTestKeep(t, chain, keepAddress, 2)
+
+	hash := signatureHash(keepAddress, digest, chain.ChainID())
+	share := signTestShare(t, signerKey, hash)
+
+	if err := memberChains[0].SubmitSignature(keepAddress, digest, 0, share); err != nil {
+		t.Fatal(err)
+	}
+	if err := memberChains[1].SubmitSignature(keepAddress, digest, 1, share); err != nil {
+		t.Fatal(err)
+	}
+
+	err := memberChains[2].SubmitSignature(keepAddress, digest, 2, share)
+	if err == nil {
+		t.Error("expected an error for a share submitted after aggregation; got none")
+	}
+}
+
+// TestSubmitSignature_ConcurrentSubmissions fires many SubmitSignature
+// calls for the same keep concurrently, each over a distinct digest, and
+// asserts every one lands: the shared per-operator nonce queue must not
+// drop or double-allocate a nonce under concurrent use.
+func TestSubmitSignature_ConcurrentSubmissions(t *testing.T) {
+	chain := initializeLocalChain()
+	keepAddress := common.HexToAddress("0x41048F9B90290A2e96D07f537F3A7E97620E9e47")
+
+	signerKey, _ := thresholdTestKeep(t, chain, keepAddress, 1)
+
+	const submissionCount = 50
+
+	var wg sync.WaitGroup
+	errs := make(chan error, submissionCount)
+
+	for i := 0; i < submissionCount; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			digest := [32]byte{byte(i), byte(i >> 8)}
+			hash := signatureHash(keepAddress, digest, chain.ChainID())
+			share := signTestShare(t, signerKey, hash)
+
+			errs <- chain.SubmitSignature(keepAddress, digest, 0, share)
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	for i := 0; i < submissionCount; i++ {
+		digest := [32]byte{byte(i), byte(i >> 8)}
+		if _, err := chain.GetLatestSignature(keepAddress, digest); err != nil {
+			t.Errorf("missing assembled signature for digest %v: [%v]", digest, err)
+		}
+	}
+}
+
+// TestCreateKeep_DuplicateMember guards against a keep being created with
+// the same member address occupying more than one seat: that would let a
+// single operator pass authorizeMemberIndex for multiple indices and
+// single-handedly satisfy honestThreshold.
+func TestCreateKeep_DuplicateMember(t *testing.T) {
+	chain := initializeLocalChain()
+	keepAddress := common.HexToAddress("0x41048F9B90290A2e96D07f537F3A7E97620E9e47")
+	operatorAddress := chain.OperatorAddress()
+
+	err := chain.CreateKeep(
+		keepAddress,
+		[]common.Address{operatorAddress, operatorAddress},
+		2,
+	)
+	if err == nil {
+		t.Error("expected an error for a keep with a duplicated member; got none")
+	}
+}
+
+// TestCreateKeep_InvalidHonestThreshold guards against an honestThreshold
+// that can never be meaningfully satisfied: zero lets the first submitted
+// share "cross" the threshold on its own, and a threshold above the member
+// count can never be satisfied at all.
+func TestCreateKeep_InvalidHonestThreshold(t *testing.T) {
+	chain := initializeLocalChain()
+	members := []common.Address{chain.OperatorAddress()}
+
+	zeroThresholdKeep := common.HexToAddress("0x41048F9B90290A2e96D07f537F3A7E97620E9e47")
+	if err := chain.CreateKeep(zeroThresholdKeep, members, 0); err == nil {
+		t.Error("expected an error for a zero honest threshold; got none")
+	}
+
+	tooHighThresholdKeep := common.HexToAddress("0x65ea55c1f10491038425725dc00dffeab2a1e28")
+	if err := chain.CreateKeep(tooHighThresholdKeep, members, 2); err == nil {
+		t.Error("expected an error for an honest threshold above the member count; got none")
+	}
+}
+
+func TestSubmitKeepPublicKey_UnauthorizedOperator(t *testing.T) {
+	chain := initializeLocalChain()
+	keepAddress := common.HexToAddress("0x41048F9B90290A2e96D07f537F3A7E97620E9e47")
+	otherMember := common.HexToAddress("0x65ea55c1f10491038425725dc00dffeab2a1e28")
+
+	err := chain.CreateKeep(keepAddress, []common.Address{otherMember}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = chain.SubmitKeepPublicKey(keepAddress, [64]byte{11, 12, 13, 14, 15, 16})
+	if err == nil {
+		t.Error("expected an error for an operator that is not a member of the keep; got none")
+	}
+}
+
+func TestNotifyInactiveMembers(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	chain := initializeLocalChain()
+	keepAddress := common.HexToAddress("0x41048F9B90290A2e96D07f537F3A7E97620E9e47")
+	memberIndices := []uint8{2, 3}
+	signatures := []byte{1, 2, 3}
+
+	members := []common.Address{
+		chain.OperatorAddress(),
+		common.HexToAddress("0x65ea55c1f10491038425725dc00dffeab2a1e28"),
+		common.HexToAddress("0x70997970c51812dc3a010c7d01b50e0d17dc79c8"),
+		common.HexToAddress("0x3c44cdddb6a900fa2b585dd299e03d12fa4293bc"),
+	}
+
+	err := chain.CreateKeep(keepAddress, members, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eventFired := make(chan *eth.InactivityClaimedEvent)
+	subscription, err := chain.OnInactivityClaimed(
+		func(event *eth.InactivityClaimedEvent) {
+			eventFired <- event
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer subscription.Unsubscribe()
+
+	nonce, err := chain.GetInactivityNonce(keepAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = chain.NotifyInactiveMembers(keepAddress, memberIndices, nonce, signatures)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedEvent := &eth.InactivityClaimedEvent{
+		KeepAddress:   keepAddress,
+		Nonce:         nonce,
+		MemberIndices: memberIndices,
+	}
+
+	select {
+	case event := <-eventFired:
+		if !reflect.DeepEqual(event, expectedEvent) {
+			t.Fatalf(
+				"unexpected inactivity claimed event\nexpected: [%v]\nactual:   [%v]",
+				expectedEvent,
+				event,
+			)
+		}
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	updatedNonce, err := chain.GetInactivityNonce(keepAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updatedNonce.Cmp(big.NewInt(1)) != 0 {
+		t.Errorf(
+			"unexpected inactivity nonce\nexpected: [%v]\nactual:   [%v]",
+			1,
+			updatedNonce,
+		)
+	}
+
+	err = chain.NotifyInactiveMembers(keepAddress, memberIndices, nonce, signatures)
+	if err == nil {
+		t.Error("expected an error for a stale nonce; got none")
+	}
+
+	err = chain.NotifyInactiveMembers(keepAddress, memberIndices, updatedNonce, signatures)
+	if err == nil {
+		t.Error("expected an error for a duplicate claim against the same members; got none")
+	}
+
+	err = chain.NotifyInactiveMembers(keepAddress, memberIndices, updatedNonce, []byte{})
+	if err == nil {
+		t.Error("expected an error for an empty signature payload; got none")
+	}
+}
+
+func TestNotifyInactiveMembers_InvalidMemberIndices(t *testing.T) {
+	chain := initializeLocalChain()
+	keepAddress := common.HexToAddress("0x41048F9B90290A2e96D07f537F3A7E97620E9e47")
+	signatures := []byte{1, 2, 3}
+
+	err := chain.CreateKeep(keepAddress, []common.Address{chain.OperatorAddress()}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nonce, err := chain.GetInactivityNonce(keepAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = chain.NotifyInactiveMembers(keepAddress, []uint8{2, 3}, nonce, signatures)
+	if err == nil {
+		t.Error("expected an error for member indices out of range for the keep; got none")
+	}
+
+	err = chain.NotifyInactiveMembers(keepAddress, []uint8{}, nonce, signatures)
+	if err == nil {
+		t.Error("expected an error for an empty memberIndices claim; got none")
+	}
 }
 
 func initializeLocalChain() *LocalChain {
 	keeps = make(map[eth.KeepAddress]*localKeep)
-	return Connect().(*LocalChain)
+
+	chain, err := Connect(nil, "")
+	if err != nil {
+		panic(err)
+	}
+
+	return chain.(*LocalChain)
 }
@@ -0,0 +1,24 @@
+// Package ecdsa contains types shared between the chain abstraction and the
+// client-side ECDSA keep protocol implementation.
+package ecdsa
+
+import "math/big"
+
+// Signature is a signature produced by a keep over a given digest. V is an
+// EIP-155-style recovery parameter (35 + 2*chainID + recid) that lets the
+// signer's public key be recovered from R, S, and the chain id the
+// signature was produced for.
+type Signature struct {
+	R *big.Int
+	S *big.Int
+	V *big.Int
+}
+
+// SignatureShare is a single keep member's partial contribution, identified
+// by R and S, toward a threshold Signature over a digest. It carries no V,
+// since a recovery parameter is only meaningful once enough shares have
+// been combined into a canonical Signature.
+type SignatureShare struct {
+	R *big.Int
+	S *big.Int
+}
@@ -0,0 +1,74 @@
+// Package operator manages the ECDSA key pair identifying a keep-tecdsa
+// client to the rest of the network and to the chain.
+package operator
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// keyFileName is the name of the file the operator key is persisted under,
+// relative to the node's configured data directory.
+const keyFileName = "operator.key"
+
+// LoadOrGenerateKey resolves the operator's ECDSA private key.
+//
+// If privateKey is non-nil, it is returned as-is. Otherwise, if dataDir is
+// non-empty, the key is loaded from <dataDir>/operator.key; if that file
+// does not exist yet, a new key is generated and persisted there with 0600
+// permissions so it survives process restarts. Any other error reading the
+// file (corruption, a truncated write, a permission error) is propagated
+// rather than silently minting a new operator identity. If dataDir is
+// empty, a new key is generated and kept in memory only.
+func LoadOrGenerateKey(
+	privateKey *ecdsa.PrivateKey,
+	dataDir string,
+) (*ecdsa.PrivateKey, error) {
+	if privateKey != nil {
+		return privateKey, nil
+	}
+
+	if dataDir == "" {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate operator key: [%v]", err)
+		}
+
+		return key, nil
+	}
+
+	keyFilePath := filepath.Join(dataDir, keyFileName)
+
+	key, err := crypto.LoadECDSA(keyFilePath)
+	switch {
+	case err == nil:
+		return key, nil
+	case os.IsNotExist(err):
+		// fall through to generate a new key below
+	default:
+		return nil, fmt.Errorf(
+			"failed to load operator key from [%s]: [%v]",
+			keyFilePath,
+			err,
+		)
+	}
+
+	key, err = crypto.GenerateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate operator key: [%v]", err)
+	}
+
+	if err := crypto.SaveECDSA(keyFilePath, key); err != nil {
+		return nil, fmt.Errorf(
+			"failed to persist operator key to [%s]: [%v]",
+			keyFilePath,
+			err,
+		)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,95 @@
+package operator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestLoadOrGenerateKey_ExplicitKey(t *testing.T) {
+	explicitKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := LoadOrGenerateKey(explicitKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key != explicitKey {
+		t.Errorf("expected the explicitly-supplied key to be returned unchanged")
+	}
+}
+
+func TestLoadOrGenerateKey_Ephemeral(t *testing.T) {
+	key1, err := LoadOrGenerateKey(nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key2, err := LoadOrGenerateKey(nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if crypto.PubkeyToAddress(key1.PublicKey) == crypto.PubkeyToAddress(key2.PublicKey) {
+		t.Errorf("expected two distinct ephemeral keys, got the same address twice")
+	}
+}
+
+func TestLoadOrGenerateKey_GenerateAndPersist(t *testing.T) {
+	dataDir, err := ioutil.TempDir("", "operator-key-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dataDir)
+
+	generatedKey, err := LoadOrGenerateKey(nil, dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyFilePath := filepath.Join(dataDir, keyFileName)
+	info, err := os.Stat(keyFilePath)
+	if err != nil {
+		t.Fatalf("expected operator key to be persisted: [%v]", err)
+	}
+
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("unexpected key file permissions\nexpected: %o\nactual:   %o", 0600, perm)
+	}
+
+	loadedKey, err := LoadOrGenerateKey(nil, dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if crypto.PubkeyToAddress(generatedKey.PublicKey) != crypto.PubkeyToAddress(loadedKey.PublicKey) {
+		t.Errorf(
+			"expected the previously-generated key to be loaded back from disk\nexpected: [%s]\nactual:   [%s]",
+			crypto.PubkeyToAddress(generatedKey.PublicKey).String(),
+			crypto.PubkeyToAddress(loadedKey.PublicKey).String(),
+		)
+	}
+}
+
+func TestLoadOrGenerateKey_CorruptedKeyFile(t *testing.T) {
+	dataDir, err := ioutil.TempDir("", "operator-key-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dataDir)
+
+	keyFilePath := filepath.Join(dataDir, keyFileName)
+	if err := ioutil.WriteFile(keyFilePath, []byte("not a valid key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadOrGenerateKey(nil, dataDir); err == nil {
+		t.Error("expected an error for a corrupted operator key file; got none")
+	}
+}
@@ -0,0 +1,176 @@
+// Package txqueue provides a client-side transaction submission pipeline.
+// It allocates monotonically increasing, per-operator nonces the way
+// bind.BoundContract.transact does on a single ethereum account, but lets
+// several submissions be in flight for the same operator at once instead of
+// waiting for each one to land on chain before the next nonce is assigned.
+package txqueue
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TransactOpts customizes a single submission made through a Queue.
+type TransactOpts struct {
+	// Nonce overrides the nonce the queue would otherwise allocate for this
+	// submission. Leave nil to let the queue assign the next monotonic
+	// nonce for the operator.
+	Nonce *uint64
+}
+
+// PendingNonceAt resolves the nonce a chain expects next from operator,
+// including the effect of any transactions still pending in its mempool.
+// It is called at most once per operator, the first time the queue sees a
+// submission from them, and again any time the chain reports a nonce as
+// stale.
+type PendingNonceAt func(ctx context.Context, operator common.Address) (uint64, error)
+
+// Queue pipelines transactions on behalf of potentially many operators,
+// assigning each one a monotonically increasing nonce and resyncing
+// against PendingNonceAt whenever the chain reports the allocated nonce as
+// stale ("nonce too low").
+type Queue struct {
+	pendingNonceAt PendingNonceAt
+
+	mutex         sync.Mutex
+	nextNonce     map[common.Address]uint64
+	nonceResolved map[common.Address]bool
+	pendingCount  int
+	wg            sync.WaitGroup
+}
+
+// New creates a Queue that resolves starting nonces with pendingNonceAt.
+func New(pendingNonceAt PendingNonceAt) *Queue {
+	return &Queue{
+		pendingNonceAt: pendingNonceAt,
+		nextNonce:      make(map[common.Address]uint64),
+		nonceResolved:  make(map[common.Address]bool),
+	}
+}
+
+// Send allocates a nonce for operator and invokes action with it. If action
+// fails with a "nonce too low" error, the queue resyncs its view of
+// operator's nonce against PendingNonceAt and retries action exactly once
+// with the resynced nonce.
+func (q *Queue) Send(
+	ctx context.Context,
+	operator common.Address,
+	opts *TransactOpts,
+	action func(nonce uint64) error,
+) error {
+	q.mutex.Lock()
+	q.pendingCount++
+	q.wg.Add(1)
+	q.mutex.Unlock()
+
+	defer func() {
+		q.mutex.Lock()
+		q.pendingCount--
+		q.mutex.Unlock()
+		q.wg.Done()
+	}()
+
+	nonce, err := q.allocateNonce(ctx, operator, opts)
+	if err != nil {
+		return err
+	}
+
+	err = action(nonce)
+	if !isNonceTooLow(err) {
+		return err
+	}
+
+	if err := q.resync(ctx, operator); err != nil {
+		return err
+	}
+
+	nonce, err = q.allocateNonce(ctx, operator, opts)
+	if err != nil {
+		return err
+	}
+
+	return action(nonce)
+}
+
+func (q *Queue) allocateNonce(
+	ctx context.Context,
+	operator common.Address,
+	opts *TransactOpts,
+) (uint64, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if opts != nil && opts.Nonce != nil {
+		return *opts.Nonce, nil
+	}
+
+	if !q.nonceResolved[operator] {
+		nonce, err := q.pendingNonceAt(ctx, operator)
+		if err != nil {
+			return 0, fmt.Errorf(
+				"failed to resolve starting nonce for operator [%s]: [%v]",
+				operator.String(),
+				err,
+			)
+		}
+
+		q.nextNonce[operator] = nonce
+		q.nonceResolved[operator] = true
+	}
+
+	nonce := q.nextNonce[operator]
+	q.nextNonce[operator] = nonce + 1
+
+	return nonce, nil
+}
+
+func (q *Queue) resync(ctx context.Context, operator common.Address) error {
+	nonce, err := q.pendingNonceAt(ctx, operator)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to resync nonce for operator [%s]: [%v]",
+			operator.String(),
+			err,
+		)
+	}
+
+	q.mutex.Lock()
+	q.nextNonce[operator] = nonce
+	q.mutex.Unlock()
+
+	return nil
+}
+
+// PendingCount returns the number of transactions currently submitted to
+// the queue that have not yet completed.
+func (q *Queue) PendingCount() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.pendingCount
+}
+
+// Drain blocks until every in-flight transaction has completed, or ctx is
+// done, whichever happens first.
+func (q *Queue) Drain(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func isNonceTooLow(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "nonce too low")
+}
@@ -0,0 +1,179 @@
+package txqueue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestQueue_MonotonicNonces(t *testing.T) {
+	operator := common.HexToAddress("0x41048F9B90290A2e96D07f537F3A7E97620E9e47")
+	queue := New(func(ctx context.Context, operator common.Address) (uint64, error) {
+		return 0, nil
+	})
+
+	const submissionCount = 50
+
+	var mutex sync.Mutex
+	seenNonces := make(map[uint64]bool)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, submissionCount)
+
+	for i := 0; i < submissionCount; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := queue.Send(
+				context.Background(),
+				operator,
+				nil,
+				func(nonce uint64) error {
+					mutex.Lock()
+					defer mutex.Unlock()
+
+					if seenNonces[nonce] {
+						return fmt.Errorf("nonce [%v] allocated twice", nonce)
+					}
+					seenNonces[nonce] = true
+
+					return nil
+				},
+			)
+			errs <- err
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	if len(seenNonces) != submissionCount {
+		t.Errorf(
+			"unexpected number of distinct nonces allocated\nexpected: %v\nactual:   %v",
+			submissionCount,
+			len(seenNonces),
+		)
+	}
+}
+
+func TestQueue_NonceOverride(t *testing.T) {
+	operator := common.HexToAddress("0x41048F9B90290A2e96D07f537F3A7E97620E9e47")
+	queue := New(func(ctx context.Context, operator common.Address) (uint64, error) {
+		return 5, nil
+	})
+
+	override := uint64(42)
+	var observedNonce uint64
+
+	err := queue.Send(
+		context.Background(),
+		operator,
+		&TransactOpts{Nonce: &override},
+		func(nonce uint64) error {
+			observedNonce = nonce
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if observedNonce != override {
+		t.Errorf(
+			"unexpected nonce\nexpected: %v\nactual:   %v",
+			override,
+			observedNonce,
+		)
+	}
+}
+
+func TestQueue_ResyncOnStaleNonce(t *testing.T) {
+	operator := common.HexToAddress("0x41048F9B90290A2e96D07f537F3A7E97620E9e47")
+
+	var pendingNonceAtCalls int
+	queue := New(func(ctx context.Context, operator common.Address) (uint64, error) {
+		pendingNonceAtCalls++
+		return 10, nil
+	})
+
+	attempt := 0
+	err := queue.Send(
+		context.Background(),
+		operator,
+		nil,
+		func(nonce uint64) error {
+			attempt++
+			if attempt == 1 {
+				return fmt.Errorf("nonce too low")
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if attempt != 2 {
+		t.Errorf("expected the action to be retried once after a resync\nexpected: %v\nactual:   %v", 2, attempt)
+	}
+
+	if pendingNonceAtCalls != 2 {
+		t.Errorf(
+			"expected PendingNonceAt to be called once at startup and once to resync\nexpected: %v\nactual:   %v",
+			2,
+			pendingNonceAtCalls,
+		)
+	}
+}
+
+func TestQueue_PendingCountAndDrain(t *testing.T) {
+	operator := common.HexToAddress("0x41048F9B90290A2e96D07f537F3A7E97620E9e47")
+	queue := New(func(ctx context.Context, operator common.Address) (uint64, error) {
+		return 0, nil
+	})
+
+	release := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		queue.Send(context.Background(), operator, nil, func(nonce uint64) error {
+			<-release
+			return nil
+		})
+		close(done)
+	}()
+
+	for queue.PendingCount() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if count := queue.PendingCount(); count != 1 {
+		t.Errorf("unexpected pending count\nexpected: %v\nactual:   %v", 1, count)
+	}
+
+	close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	if err := queue.Drain(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	<-done
+
+	if count := queue.PendingCount(); count != 0 {
+		t.Errorf("unexpected pending count after drain\nexpected: %v\nactual:   %v", 0, count)
+	}
+}